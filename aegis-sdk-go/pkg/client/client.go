@@ -3,118 +3,368 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
+	"strconv"
+	"sync"
+	"time"
 )
 
+// defaultMaxRetries is how many times doRequest retries a request that
+// fails with a retryable status code or network error.
+const defaultMaxRetries = 3
+
+// defaultRequestTimeout bounds how long a request may run when the caller
+// hasn't set a deadline on its context or overridden the HTTP client. Aegis
+// OS may be called on the boot path, so a stuck server must not hang the
+// caller indefinitely.
+const defaultRequestTimeout = 30 * time.Second
+
 // Client is the Aegis OS API client
 type Client struct {
 	BaseURL string
-	APIKey  string
-	UserID  string
-	client  *http.Client
+
+	// ExpectedIssuer and ExpectedAudience, if set, are checked against the
+	// iss/aud claims of license tokens passed to VerifyLicenseToken.
+	ExpectedIssuer   string
+	ExpectedAudience string
+
+	// AllowedTiers, if non-empty, restricts VerifyLicenseToken to tokens
+	// whose tier claim is in the list.
+	AllowedTiers []string
+
+	auth       AuthHandler
+	client     *http.Client
+	maxRetries int
+
+	mu   sync.Mutex
+	jwks *keyCache
 }
 
-// NewClient creates a new Aegis OS API client
-func NewClient(baseURL, apiKey, userID string) *Client {
-	return &Client{
-		BaseURL: baseURL,
-		APIKey:  apiKey,
-		UserID:  userID,
-		client:  &http.Client{},
+// Close releases background resources started by the Client, such as the
+// JWKS refresh goroutine used by VerifyLicenseToken.
+func (c *Client) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.jwks != nil {
+		c.jwks.stop()
 	}
 }
 
-// doRequest performs an HTTP request
-func (c *Client) doRequest(method, endpoint string, body interface{}) ([]byte, error) {
-	url := fmt.Sprintf("%s%s", c.BaseURL, endpoint)
-	
-	var reqBody io.Reader
+// Option configures a Client created by NewClient.
+type Option func(*Client)
+
+// WithAuth sets the AuthHandler used to authenticate every request. Without
+// it, requests are sent unauthenticated.
+func WithAuth(auth AuthHandler) Option {
+	return func(c *Client) {
+		c.auth = auth
+	}
+}
+
+// WithHTTPClient overrides the *http.Client used to send requests, e.g. to
+// configure mutual TLS via NewMTLSHTTPClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.client = httpClient
+	}
+}
+
+// WithTimeout sets the timeout on the Client's underlying *http.Client.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.client.Timeout = timeout
+	}
+}
+
+// WithMaxRetries overrides how many times a request is retried after a
+// retryable status code (429, 503) or network error. Defaults to 3.
+// Negative values are clamped to 0 (no retries) rather than disabling the
+// request entirely.
+func WithMaxRetries(maxRetries int) Option {
+	return func(c *Client) {
+		if maxRetries < 0 {
+			maxRetries = 0
+		}
+		c.maxRetries = maxRetries
+	}
+}
+
+// WithIssuer sets the issuer that VerifyLicenseToken requires license
+// tokens' iss claim to match. Unset, the iss claim is not checked.
+func WithIssuer(issuer string) Option {
+	return func(c *Client) {
+		c.ExpectedIssuer = issuer
+	}
+}
+
+// WithAudience sets the audience that VerifyLicenseToken requires license
+// tokens' aud claim to match. Unset, the aud claim is not checked.
+func WithAudience(audience string) Option {
+	return func(c *Client) {
+		c.ExpectedAudience = audience
+	}
+}
+
+// WithAllowedTiers restricts VerifyLicenseToken to license tokens whose
+// tier claim is one of tiers. Unset, any non-empty tier is accepted.
+func WithAllowedTiers(tiers ...string) Option {
+	return func(c *Client) {
+		c.AllowedTiers = tiers
+	}
+}
+
+// NewClient creates a new Aegis OS API client. By default it is
+// unauthenticated and its underlying *http.Client has a defaultRequestTimeout
+// timeout; pass WithAuth and WithTimeout (or WithHTTPClient) to configure it.
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		BaseURL:    baseURL,
+		client:     &http.Client{Timeout: defaultRequestTimeout},
+		maxRetries: defaultMaxRetries,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// doRequest performs an HTTP request against context.Background(). It exists
+// for callers that don't need cancellation; prefer doRequestCtx.
+func (c *Client) doRequest(method, endpoint string, body interface{}, v interface{}) error {
+	return c.doRequestCtx(context.Background(), method, endpoint, body, v)
+}
+
+// doRequestCtx performs an HTTP request and decodes a successful response
+// body into v, retrying automatically only if method is idempotent (see
+// isIdempotentMethod). Use doRequestIdempotentCtx for endpoints that are
+// POSTs but are purely reads, like ValidateLicense.
+func (c *Client) doRequestCtx(ctx context.Context, method, endpoint string, body interface{}, v interface{}) error {
+	return c.doRequestRetryCtx(ctx, method, endpoint, body, v, isIdempotentMethod(method))
+}
+
+// doRequestIdempotentCtx is like doRequestCtx but retries regardless of
+// method. Only call it for requests that are safe to resend verbatim after
+// a network error — a write endpoint retried here could be applied twice
+// (e.g. provisioning a duplicate license) if the server processed the first
+// attempt before the connection dropped.
+func (c *Client) doRequestIdempotentCtx(ctx context.Context, method, endpoint string, body interface{}, v interface{}) error {
+	return c.doRequestRetryCtx(ctx, method, endpoint, body, v, true)
+}
+
+// isIdempotentMethod reports whether method can be safely retried without
+// risking duplicate server-side effects.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// doRequestRetryCtx performs an HTTP request and decodes a successful
+// response body into v. If the server responds with a non-2xx status, the
+// body is parsed as an APIError and returned as the error. When retryable is
+// true, the request is retried on 429/503 (honoring Retry-After) and on
+// network errors, with exponential backoff and jitter between attempts;
+// otherwise it is sent at most once.
+func (c *Client) doRequestRetryCtx(ctx context.Context, method, endpoint string, body interface{}, v interface{}, retryable bool) error {
+	var jsonBody []byte
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
+		var err error
+		jsonBody, err = json.Marshal(body)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		reqBody = bytes.NewBuffer(jsonBody)
 	}
-	
-	req, err := http.NewRequest(method, url, reqBody)
-	if err != nil {
-		return nil, err
+
+	url := fmt.Sprintf("%s%s", c.BaseURL, endpoint)
+	backoff := 250 * time.Millisecond
+
+	maxAttempts := 0
+	if retryable {
+		maxAttempts = c.maxRetries
 	}
-	
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-Key", c.APIKey)
-	req.Header.Set("X-User-ID", c.UserID)
-	
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
+
+	var lastErr error
+	var minWait time.Duration
+	for attempt := 0; attempt <= maxAttempts; attempt++ {
+		if attempt > 0 {
+			wait := retryJitter(backoff)
+			if wait < minWait {
+				wait = minWait
+			}
+			minWait = 0
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+			backoff *= 2
+		}
+
+		var reqBody io.Reader
+		if jsonBody != nil {
+			reqBody = bytes.NewReader(jsonBody)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		if c.auth != nil {
+			if err := c.auth.Apply(req); err != nil {
+				return fmt.Errorf("aegis: applying auth: %w", err)
+			}
+		}
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			lastErr = &APIError{StatusCode: resp.StatusCode, Message: resp.Status}
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				backoff = retryAfter
+				minWait = retryAfter
+			}
+			continue
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			apiErr := &APIError{StatusCode: resp.StatusCode}
+			if len(respBody) > 0 {
+				if jsonErr := json.Unmarshal(respBody, apiErr); jsonErr != nil {
+					apiErr.Message = string(respBody)
+				}
+			}
+			if apiErr.Message == "" {
+				apiErr.Message = resp.Status
+			}
+			return apiErr
+		}
+
+		if v == nil || len(respBody) == 0 {
+			return nil
+		}
+
+		return json.Unmarshal(respBody, v)
+	}
+
+	return lastErr
+}
+
+// parseRetryAfter parses a Retry-After header, which may be either a
+// number of seconds or an HTTP date.
+func parseRetryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if when, err := http.ParseTime(header); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d, true
+		}
 	}
-	defer resp.Body.Close()
-	
-	return io.ReadAll(resp.Body)
+	return 0, false
+}
+
+// retryJitter returns d plus or minus up to 20% to avoid synchronized
+// retries across many clients.
+func retryJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	spread := int64(d) / 5
+	if spread <= 0 {
+		return d
+	}
+	return d + time.Duration(rand.Int63n(2*spread)-spread)
 }
 
 // ValidateLicense validates a license key
-func (c *Client) ValidateLicense(key string) (map[string]interface{}, error) {
+func (c *Client) ValidateLicense(key string) (*LicenseValidation, error) {
+	return c.ValidateLicenseCtx(context.Background(), key)
+}
+
+// ValidateLicenseCtx validates a license key, honoring ctx for cancellation,
+// deadlines, and retries. Despite being a POST, validation is a pure read
+// with no server-side side effects, so it is safe to retry automatically.
+func (c *Client) ValidateLicenseCtx(ctx context.Context, key string) (*LicenseValidation, error) {
 	body := map[string]string{"key": key}
-	resp, err := c.doRequest("POST", "/api/v1/license/validate", body)
-	if err != nil {
-		return nil, err
-	}
-	
-	var result map[string]interface{}
-	if err := json.Unmarshal(resp, &result); err != nil {
+
+	var result LicenseValidation
+	if err := c.doRequestIdempotentCtx(ctx, "POST", "/api/v1/license/validate", body, &result); err != nil {
 		return nil, err
 	}
-	
-	return result, nil
+
+	return &result, nil
 }
 
 // GetTiers gets all available tiers
-func (c *Client) GetTiers() (map[string]interface{}, error) {
-	resp, err := c.doRequest("GET", "/api/v1/tiers", nil)
-	if err != nil {
-		return nil, err
-	}
-	
-	var result map[string]interface{}
-	if err := json.Unmarshal(resp, &result); err != nil {
+func (c *Client) GetTiers() (*TierCatalog, error) {
+	return c.GetTiersCtx(context.Background())
+}
+
+// GetTiersCtx gets all available tiers, honoring ctx for cancellation,
+// deadlines, and retries.
+func (c *Client) GetTiersCtx(ctx context.Context) (*TierCatalog, error) {
+	var result TierCatalog
+	if err := c.doRequestCtx(ctx, "GET", "/api/v1/tiers", nil, &result); err != nil {
 		return nil, err
 	}
-	
-	return result, nil
+
+	return &result, nil
 }
 
 // GetSystemStatus gets system status
-func (c *Client) GetSystemStatus() (map[string]interface{}, error) {
-	resp, err := c.doRequest("GET", "/api/v1/system/status", nil)
-	if err != nil {
-		return nil, err
-	}
-	
-	var result map[string]interface{}
-	if err := json.Unmarshal(resp, &result); err != nil {
+func (c *Client) GetSystemStatus() (*SystemStatus, error) {
+	return c.GetSystemStatusCtx(context.Background())
+}
+
+// GetSystemStatusCtx gets system status, honoring ctx for cancellation,
+// deadlines, and retries.
+func (c *Client) GetSystemStatusCtx(ctx context.Context) (*SystemStatus, error) {
+	var result SystemStatus
+	if err := c.doRequestCtx(ctx, "GET", "/api/v1/system/status", nil, &result); err != nil {
 		return nil, err
 	}
-	
-	return result, nil
+
+	return &result, nil
 }
 
 // GetSecurityCheck gets security status
-func (c *Client) GetSecurityCheck() (map[string]interface{}, error) {
-	resp, err := c.doRequest("GET", "/api/v1/security/check", nil)
-	if err != nil {
-		return nil, err
-	}
-	
-	var result map[string]interface{}
-	if err := json.Unmarshal(resp, &result); err != nil {
+func (c *Client) GetSecurityCheck() (*SecurityReport, error) {
+	return c.GetSecurityCheckCtx(context.Background())
+}
+
+// GetSecurityCheckCtx gets security status, honoring ctx for cancellation,
+// deadlines, and retries.
+func (c *Client) GetSecurityCheckCtx(ctx context.Context) (*SecurityReport, error) {
+	var result SecurityReport
+	if err := c.doRequestCtx(ctx, "GET", "/api/v1/security/check", nil, &result); err != nil {
 		return nil, err
 	}
-	
-	return result, nil
+
+	return &result, nil
 }
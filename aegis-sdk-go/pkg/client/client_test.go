@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoRequestRetriesOnServiceUnavailable(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithTimeout(5*time.Second))
+
+	var result SystemStatus
+	if err := c.doRequestCtx(context.Background(), http.MethodGet, "/api/v1/system/status", nil, &result); err != nil {
+		t.Fatalf("doRequestCtx returned error: %v", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestDoRequestDoesNotRetryNonIdempotentPost(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithTimeout(5*time.Second))
+
+	err := c.doRequestCtx(context.Background(), http.MethodPost, "/api/v1/licenses", &CreateLicenseRequest{Tier: "pro"}, nil)
+	if err == nil {
+		t.Fatal("expected an error from the 503 response")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Fatalf("expected exactly 1 attempt for a non-idempotent POST, got %d", got)
+	}
+}
+
+func TestDoRequestIdempotentCtxRetriesPost(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"valid":true}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithTimeout(5*time.Second))
+
+	var result LicenseValidation
+	err := c.doRequestIdempotentCtx(context.Background(), http.MethodPost, "/api/v1/license/validate", map[string]string{"key": "k"}, &result)
+	if err != nil {
+		t.Fatalf("doRequestIdempotentCtx returned error: %v", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Fatalf("expected 2 attempts, got %d", got)
+	}
+}
+
+func TestWithMaxRetriesClampsNegative(t *testing.T) {
+	c := NewClient("http://example.invalid", WithMaxRetries(-5))
+	if c.maxRetries != 0 {
+		t.Fatalf("expected maxRetries to clamp to 0, got %d", c.maxRetries)
+	}
+}
+
+func TestNewClientHasDefaultTimeout(t *testing.T) {
+	c := NewClient("http://example.invalid")
+	if c.client.Timeout != defaultRequestTimeout {
+		t.Fatalf("expected default timeout %v, got %v", defaultRequestTimeout, c.client.Timeout)
+	}
+}
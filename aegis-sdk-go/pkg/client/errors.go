@@ -0,0 +1,23 @@
+package client
+
+import "fmt"
+
+// APIError represents a structured error returned by the Aegis OS API for
+// any non-2xx response.
+type APIError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id"`
+
+	// StatusCode is the HTTP status code that produced this error. It is
+	// populated by the client and is not part of the API response body.
+	StatusCode int `json:"-"`
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("aegis: %s (code=%s, request_id=%s)", e.Message, e.Code, e.RequestID)
+	}
+	return fmt.Sprintf("aegis: %s (code=%s)", e.Message, e.Code)
+}
@@ -0,0 +1,340 @@
+package client
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jwksKeyTTL is how long a cached public key is trusted before it is
+// considered stale and re-fetched.
+const jwksKeyTTL = 15 * time.Minute
+
+// jwksRefreshInterval is how often the background refresh goroutine
+// re-fetches the JWKS document, independent of individual key TTLs.
+const jwksRefreshInterval = 15 * time.Minute
+
+// PublicKeyLookupResult is a single JWKS key resolved to a usable
+// crypto.PublicKey, as returned by keyCache lookups. This mirrors how
+// Dendrite's keydb stores parsed keys rather than raw JSON so hot-path
+// verification never re-parses a key it has already seen.
+type PublicKeyLookupResult struct {
+	Kid       string
+	Alg       string
+	Key       crypto.PublicKey
+	ExpiresAt time.Time
+}
+
+func (r *PublicKeyLookupResult) expired() bool {
+	return time.Now().After(r.ExpiresAt)
+}
+
+// keyCache holds JWKS keys keyed by kid, refreshing them from the issuer's
+// well-known endpoint on a cache miss or on a periodic timer.
+type keyCache struct {
+	client *Client
+
+	mu   sync.RWMutex
+	keys map[string]*PublicKeyLookupResult
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+func newKeyCache(c *Client) *keyCache {
+	kc := &keyCache{
+		client: c,
+		keys:   make(map[string]*PublicKeyLookupResult),
+		stopCh: make(chan struct{}),
+	}
+	go kc.refreshLoop()
+	return kc
+}
+
+// refreshLoop periodically re-fetches the JWKS document in the background
+// so that hot-path verification almost never pays for a network round-trip.
+func (kc *keyCache) refreshLoop() {
+	ticker := time.NewTicker(jwksRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-kc.stopCh:
+			return
+		case <-ticker.C:
+			_ = kc.refresh(context.Background())
+		}
+	}
+}
+
+// stop terminates the background refresh goroutine.
+func (kc *keyCache) stop() {
+	kc.stopOnce.Do(func() {
+		close(kc.stopCh)
+	})
+}
+
+// get returns the cached key for kid, refreshing the JWKS document first if
+// the key is missing or stale.
+func (kc *keyCache) get(ctx context.Context, kid string) (*PublicKeyLookupResult, error) {
+	kc.mu.RLock()
+	result, ok := kc.keys[kid]
+	kc.mu.RUnlock()
+
+	if ok && !result.expired() {
+		return result, nil
+	}
+
+	if err := kc.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	kc.mu.RLock()
+	result, ok = kc.keys[kid]
+	kc.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("aegis: no JWKS key found for kid %q", kid)
+	}
+
+	return result, nil
+}
+
+// refresh re-fetches the JWKS document and replaces the cache contents.
+func (kc *keyCache) refresh(ctx context.Context) error {
+	var doc jwksDocument
+	if err := kc.client.doRequestCtx(ctx, "GET", "/api/v1/.well-known/jwks.json", nil, &doc); err != nil {
+		return fmt.Errorf("aegis: fetching JWKS: %w", err)
+	}
+
+	keys := make(map[string]*PublicKeyLookupResult, len(doc.Keys))
+	expiresAt := time.Now().Add(jwksKeyTTL)
+
+	for _, jwk := range doc.Keys {
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = &PublicKeyLookupResult{
+			Kid:       jwk.Kid,
+			Alg:       jwk.Alg,
+			Key:       key,
+			ExpiresAt: expiresAt,
+		}
+	}
+
+	kc.mu.Lock()
+	kc.keys = keys
+	kc.mu.Unlock()
+
+	return nil
+}
+
+// jwksDocument is the raw JSON shape of a JWKS endpoint response.
+type jwksDocument struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// jsonWebKey is a single RFC 7517 key entry, covering the RSA and EC fields
+// Aegis OS issuers use for RS256/ES256 license tokens.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+
+	// RSA fields.
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// EC fields.
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		nBytes, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		eBytes, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		n := new(big.Int).SetBytes(nBytes)
+		e := new(big.Int).SetBytes(eBytes)
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		xBytes, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		yBytes, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("aegis: unsupported EC curve %q", k.Crv)
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(xBytes),
+			Y:     new(big.Int).SetBytes(yBytes),
+		}, nil
+	default:
+		return nil, fmt.Errorf("aegis: unsupported key type %q", k.Kty)
+	}
+}
+
+// LicenseClaims are the claims carried by a signed license token.
+type LicenseClaims struct {
+	Issuer    string `json:"iss"`
+	Audience  string `json:"aud"`
+	ExpiresAt int64  `json:"exp"`
+	NotBefore int64  `json:"nbf"`
+	Tier      string `json:"tier"`
+	Seats     int    `json:"seats"`
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// VerifyLicenseToken verifies a license presented as a signed JWT (RS256 or
+// ES256), using keys fetched from the issuer's JWKS endpoint and cached by
+// kid. It validates the signature plus the exp/nbf claims, requires a
+// non-empty tier claim (restricted to AllowedTiers if set), and checks
+// iss/aud if ExpectedIssuer/ExpectedAudience were set via NewClient options.
+// This avoids a network round-trip to ValidateLicense on every check.
+func (c *Client) VerifyLicenseToken(jwt string) (*LicenseClaims, error) {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("aegis: malformed license token")
+	}
+	headerPart, payloadPart, sigPart := parts[0], parts[1], parts[2]
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(headerPart)
+	if err != nil {
+		return nil, fmt.Errorf("aegis: decoding token header: %w", err)
+	}
+	var header jwtHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, fmt.Errorf("aegis: parsing token header: %w", err)
+	}
+
+	c.mu.Lock()
+	if c.jwks == nil {
+		c.jwks = newKeyCache(c)
+	}
+	jwks := c.jwks
+	c.mu.Unlock()
+
+	lookup, err := jwks.get(context.Background(), header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(sigPart)
+	if err != nil {
+		return nil, fmt.Errorf("aegis: decoding token signature: %w", err)
+	}
+
+	signedContent := headerPart + "." + payloadPart
+	if err := verifySignature(header.Alg, lookup.Key, signedContent, sig); err != nil {
+		return nil, err
+	}
+
+	payloadJSON, err := base64.RawURLEncoding.DecodeString(payloadPart)
+	if err != nil {
+		return nil, fmt.Errorf("aegis: decoding token payload: %w", err)
+	}
+	var claims LicenseClaims
+	if err := json.Unmarshal(payloadJSON, &claims); err != nil {
+		return nil, fmt.Errorf("aegis: parsing token claims: %w", err)
+	}
+
+	now := time.Now().Unix()
+	if claims.ExpiresAt != 0 && now >= claims.ExpiresAt {
+		return nil, errors.New("aegis: license token has expired")
+	}
+	if claims.NotBefore != 0 && now < claims.NotBefore {
+		return nil, errors.New("aegis: license token is not yet valid")
+	}
+	if c.ExpectedIssuer != "" && claims.Issuer != c.ExpectedIssuer {
+		return nil, fmt.Errorf("aegis: unexpected token issuer %q", claims.Issuer)
+	}
+	if c.ExpectedAudience != "" && claims.Audience != c.ExpectedAudience {
+		return nil, fmt.Errorf("aegis: unexpected token audience %q", claims.Audience)
+	}
+	if claims.Tier == "" {
+		return nil, errors.New("aegis: license token is missing a tier claim")
+	}
+	if len(c.AllowedTiers) > 0 && !containsTier(c.AllowedTiers, claims.Tier) {
+		return nil, fmt.Errorf("aegis: license token tier %q is not permitted", claims.Tier)
+	}
+
+	return &claims, nil
+}
+
+func containsTier(tiers []string, tier string) bool {
+	for _, t := range tiers {
+		if t == tier {
+			return true
+		}
+	}
+	return false
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signedContent string, sig []byte) error {
+	digest := sha256.Sum256([]byte(signedContent))
+
+	switch alg {
+	case "RS256":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("aegis: RS256 token signed with a non-RSA key")
+		}
+		if err := rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], sig); err != nil {
+			return fmt.Errorf("aegis: signature verification failed: %w", err)
+		}
+		return nil
+	case "ES256":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("aegis: ES256 token signed with a non-EC key")
+		}
+		if len(sig) != 64 {
+			return errors.New("aegis: malformed ES256 signature")
+		}
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(ecKey, digest[:], r, s) {
+			return errors.New("aegis: signature verification failed")
+		}
+		return nil
+	default:
+		return fmt.Errorf("aegis: unsupported signing algorithm %q", alg)
+	}
+}
@@ -0,0 +1,191 @@
+package client
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newSignedRS256Token(t *testing.T, key *rsa.PrivateKey, kid string, claims LicenseClaims) string {
+	t.Helper()
+
+	header := map[string]string{"alg": "RS256", "kid": kid}
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		t.Fatalf("marshal header: %v", err)
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("marshal claims: %v", err)
+	}
+
+	headerPart := base64.RawURLEncoding.EncodeToString(headerJSON)
+	payloadPart := base64.RawURLEncoding.EncodeToString(claimsJSON)
+	signedContent := headerPart + "." + payloadPart
+
+	digest := sha256.Sum256([]byte(signedContent))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	sigPart := base64.RawURLEncoding.EncodeToString(sig)
+	return fmt.Sprintf("%s.%s.%s", headerPart, payloadPart, sigPart)
+}
+
+func newJWKSServer(t *testing.T, key *rsa.PrivateKey, kid string) *httptest.Server {
+	t.Helper()
+
+	nBytes := key.PublicKey.N.Bytes()
+	eBytes := big.NewInt(int64(key.PublicKey.E)).Bytes()
+
+	doc := jwksDocument{
+		Keys: []jsonWebKey{
+			{
+				Kty: "RSA",
+				Kid: kid,
+				Alg: "RS256",
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(nBytes),
+				E:   base64.RawURLEncoding.EncodeToString(eBytes),
+			},
+		},
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(doc); err != nil {
+			t.Fatalf("encode jwks document: %v", err)
+		}
+	}))
+}
+
+func TestVerifyLicenseTokenValid(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	srv := newJWKSServer(t, key, "test-kid")
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithIssuer("aegis"), WithAudience("licenses"), WithAllowedTiers("pro", "enterprise"))
+
+	token := newSignedRS256Token(t, key, "test-kid", LicenseClaims{
+		Issuer:    "aegis",
+		Audience:  "licenses",
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		Tier:      "pro",
+	})
+
+	claims, err := c.VerifyLicenseToken(token)
+	if err != nil {
+		t.Fatalf("VerifyLicenseToken returned error: %v", err)
+	}
+	if claims.Tier != "pro" {
+		t.Fatalf("expected tier pro, got %q", claims.Tier)
+	}
+}
+
+func TestVerifyLicenseTokenRejectsDisallowedTier(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	srv := newJWKSServer(t, key, "test-kid")
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithAllowedTiers("enterprise"))
+
+	token := newSignedRS256Token(t, key, "test-kid", LicenseClaims{
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		Tier:      "pro",
+	})
+
+	if _, err := c.VerifyLicenseToken(token); err == nil {
+		t.Fatal("expected an error for a disallowed tier")
+	}
+}
+
+func TestVerifyLicenseTokenRejectsMissingTier(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	srv := newJWKSServer(t, key, "test-kid")
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+
+	token := newSignedRS256Token(t, key, "test-kid", LicenseClaims{
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	})
+
+	if _, err := c.VerifyLicenseToken(token); err == nil {
+		t.Fatal("expected an error for a missing tier claim")
+	}
+}
+
+func TestVerifyLicenseTokenRejectsExpired(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	srv := newJWKSServer(t, key, "test-kid")
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+
+	token := newSignedRS256Token(t, key, "test-kid", LicenseClaims{
+		ExpiresAt: time.Now().Add(-time.Hour).Unix(),
+		Tier:      "pro",
+	})
+
+	if _, err := c.VerifyLicenseToken(token); err == nil {
+		t.Fatal("expected an error for an expired token")
+	}
+}
+
+// TestVerifyLicenseTokenConcurrentLazyInit exercises the lazy keyCache
+// initialization from many goroutines at once. Run with -race to catch
+// regressions of the unlocked check-then-set bug.
+func TestVerifyLicenseTokenConcurrentLazyInit(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	srv := newJWKSServer(t, key, "test-kid")
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	token := newSignedRS256Token(t, key, "test-kid", LicenseClaims{
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		Tier:      "pro",
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.VerifyLicenseToken(token); err != nil {
+				t.Errorf("VerifyLicenseToken returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// License is a provisioned Aegis OS license.
+type License struct {
+	ID        string `json:"id"`
+	Key       string `json:"key"`
+	Tier      string `json:"tier"`
+	Seats     int    `json:"seats"`
+	ExpiresAt string `json:"expires_at"`
+	Revoked   bool   `json:"revoked"`
+	CreatedAt string `json:"created_at"`
+}
+
+// LicenseList is the result of listing licenses.
+type LicenseList struct {
+	Licenses []License `json:"licenses"`
+}
+
+// CreateLicenseRequest describes a license to provision.
+type CreateLicenseRequest struct {
+	Tier      string `json:"tier"`
+	Seats     int    `json:"seats"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+// CreateLicense provisions a new license, signed server-side, for the given
+// tier and seat count.
+func (c *Client) CreateLicense(req *CreateLicenseRequest) (*License, error) {
+	return c.CreateLicenseCtx(context.Background(), req)
+}
+
+// CreateLicenseCtx provisions a new license, honoring ctx for cancellation
+// and deadlines. The request is sent at most once; it is not retried.
+func (c *Client) CreateLicenseCtx(ctx context.Context, req *CreateLicenseRequest) (*License, error) {
+	var result License
+	if err := c.doRequestCtx(ctx, "POST", "/api/v1/licenses", req, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// ListLicenses lists all licenses visible to the caller.
+func (c *Client) ListLicenses() (*LicenseList, error) {
+	return c.ListLicensesCtx(context.Background())
+}
+
+// ListLicensesCtx lists all licenses visible to the caller, honoring ctx for
+// cancellation, deadlines, and retries.
+func (c *Client) ListLicensesCtx(ctx context.Context) (*LicenseList, error) {
+	var result LicenseList
+	if err := c.doRequestCtx(ctx, "GET", "/api/v1/licenses", nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetLicense fetches a single license by ID.
+func (c *Client) GetLicense(id string) (*License, error) {
+	return c.GetLicenseCtx(context.Background(), id)
+}
+
+// GetLicenseCtx fetches a single license by ID, honoring ctx for
+// cancellation, deadlines, and retries.
+func (c *Client) GetLicenseCtx(ctx context.Context, id string) (*License, error) {
+	var result License
+	endpoint := fmt.Sprintf("/api/v1/licenses/%s", url.PathEscape(id))
+	if err := c.doRequestCtx(ctx, "GET", endpoint, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DeleteLicense permanently deletes a license by ID.
+func (c *Client) DeleteLicense(id string) error {
+	return c.DeleteLicenseCtx(context.Background(), id)
+}
+
+// DeleteLicenseCtx permanently deletes a license by ID, honoring ctx for
+// cancellation and deadlines. The request is sent at most once; it is not
+// retried.
+func (c *Client) DeleteLicenseCtx(ctx context.Context, id string) error {
+	endpoint := fmt.Sprintf("/api/v1/licenses/%s", url.PathEscape(id))
+	return c.doRequestCtx(ctx, "DELETE", endpoint, nil, nil)
+}
+
+// RevokeLicense revokes a license by ID without deleting its record.
+func (c *Client) RevokeLicense(id string) (*License, error) {
+	return c.RevokeLicenseCtx(context.Background(), id)
+}
+
+// RevokeLicenseCtx revokes a license by ID without deleting its record,
+// honoring ctx for cancellation and deadlines. The request is sent at most
+// once; it is not retried.
+func (c *Client) RevokeLicenseCtx(ctx context.Context, id string) (*License, error) {
+	var result License
+	endpoint := fmt.Sprintf("/api/v1/licenses/%s/revoke", url.PathEscape(id))
+	if err := c.doRequestCtx(ctx, "POST", endpoint, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,45 @@
+package client
+
+// LicenseValidation is the result of validating a license key.
+type LicenseValidation struct {
+	Valid     bool   `json:"valid"`
+	Key       string `json:"key"`
+	Tier      string `json:"tier"`
+	ExpiresAt string `json:"expires_at"`
+	Seats     int    `json:"seats"`
+}
+
+// Tier describes a single license tier offered by Aegis OS.
+type Tier struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Price    int      `json:"price"`
+	Features []string `json:"features"`
+}
+
+// TierCatalog is the set of tiers returned by GetTiers.
+type TierCatalog struct {
+	Tiers []Tier `json:"tiers"`
+}
+
+// SystemStatus describes the current health of the Aegis OS system.
+type SystemStatus struct {
+	Status    string `json:"status"`
+	Uptime    int64  `json:"uptime"`
+	Version   string `json:"version"`
+	NodeCount int    `json:"node_count"`
+}
+
+// SecurityCheck is a single finding returned as part of a SecurityReport.
+type SecurityCheck struct {
+	Name     string `json:"name"`
+	Passed   bool   `json:"passed"`
+	Severity string `json:"severity"`
+	Detail   string `json:"detail"`
+}
+
+// SecurityReport is the result of a security posture check.
+type SecurityReport struct {
+	Checks []SecurityCheck `json:"checks"`
+	Score  int             `json:"score"`
+}
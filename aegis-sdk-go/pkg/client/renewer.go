@@ -0,0 +1,190 @@
+package client
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// maxConsecutiveRenewFailures is how many renewal attempts in a row may fail
+// before Renew gives up and reports a terminal error on DoneCh, rather than
+// backing off forever.
+const maxConsecutiveRenewFailures = 5
+
+// RenewerConfig configures a Renewer for a single API key.
+type RenewerConfig struct {
+	// APIKey is the key to keep alive.
+	APIKey string
+
+	// TTL is the lifetime the server assigned to APIKey. The renewer wakes
+	// up and renews before this elapses.
+	TTL time.Duration
+
+	// RenewBuffer is how far ahead of expiry the renewer attempts a renewal.
+	// Defaults to 10% of TTL if zero.
+	RenewBuffer time.Duration
+}
+
+// RenewOutput is sent on a Renewer's RenewCh after each successful renewal.
+type RenewOutput struct {
+	RenewedAt time.Time
+	TTL       time.Duration
+}
+
+// Renewer periodically renews an API key in the background so that
+// long-running processes embedding the Aegis OS client never operate on an
+// expired key. It mirrors the renew-before-expiry pattern used by Vault's
+// api.Renewer.
+type Renewer struct {
+	client *Client
+	cfg    *RenewerConfig
+
+	doneCh  chan error
+	renewCh chan *RenewOutput
+	stopCh  chan struct{}
+
+	stopOnce sync.Once
+
+	// maxFailures is how many consecutive renewal failures Renew tolerates
+	// before giving up. Defaults to maxConsecutiveRenewFailures; broken out
+	// as a field (rather than using the constant directly) so tests don't
+	// have to wait through real exponential backoff to exercise give-up.
+	maxFailures int
+}
+
+// NewRenewer creates a Renewer for cfg. Call Renew (typically in its own
+// goroutine) to start the renewal loop.
+func (c *Client) NewRenewer(cfg *RenewerConfig) (*Renewer, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("aegis: renewer config is required")
+	}
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("aegis: renewer requires an API key")
+	}
+	if cfg.TTL <= 0 {
+		return nil, fmt.Errorf("aegis: renewer requires a positive TTL")
+	}
+
+	buffer := cfg.RenewBuffer
+	if buffer <= 0 {
+		buffer = cfg.TTL / 10
+	}
+
+	renewerCfg := *cfg
+	renewerCfg.RenewBuffer = buffer
+
+	return &Renewer{
+		client:      c,
+		cfg:         &renewerCfg,
+		doneCh:      make(chan error, 1),
+		renewCh:     make(chan *RenewOutput),
+		stopCh:      make(chan struct{}),
+		maxFailures: maxConsecutiveRenewFailures,
+	}, nil
+}
+
+// DoneCh returns the channel that receives nil once the renewal loop exits
+// cleanly via Stop, or a non-nil error if it gave up after
+// maxConsecutiveRenewFailures renewal attempts in a row failed.
+func (r *Renewer) DoneCh() <-chan error {
+	return r.doneCh
+}
+
+// RenewCh returns the channel that receives a RenewOutput after each
+// successful renewal.
+func (r *Renewer) RenewCh() <-chan *RenewOutput {
+	return r.renewCh
+}
+
+// Stop terminates the renewal loop started by Renew. It is safe to call
+// more than once.
+func (r *Renewer) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+	})
+}
+
+// Renew runs the renewal loop until Stop is called or renewal fails
+// maxConsecutiveRenewFailures times in a row, in which case the last error
+// is sent on DoneCh. It is intended to be run in its own goroutine.
+func (r *Renewer) Renew() {
+	nextRenewal := r.cfg.TTL - r.cfg.RenewBuffer
+	backoff := time.Second
+	const maxBackoff = 2 * time.Minute
+
+	var consecutiveFailures int
+
+	for {
+		select {
+		case <-r.stopCh:
+			r.doneCh <- nil
+			return
+		case <-time.After(jitter(nextRenewal)):
+		}
+
+		out, err := r.renewOnce()
+		if err != nil {
+			consecutiveFailures++
+			if consecutiveFailures >= r.maxFailures {
+				r.doneCh <- fmt.Errorf("aegis: giving up after %d consecutive renewal failures: %w", consecutiveFailures, err)
+				return
+			}
+
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			select {
+			case <-r.stopCh:
+				r.doneCh <- nil
+				return
+			case <-time.After(jitter(backoff)):
+				continue
+			}
+		}
+
+		consecutiveFailures = 0
+		backoff = time.Second
+		nextRenewal = r.cfg.TTL - r.cfg.RenewBuffer
+
+		select {
+		case r.renewCh <- out:
+		case <-r.stopCh:
+			r.doneCh <- nil
+			return
+		}
+	}
+}
+
+// renewOnce calls the renew endpoint a single time.
+func (r *Renewer) renewOnce() (*RenewOutput, error) {
+	body := map[string]string{"api_key": r.cfg.APIKey}
+
+	var result struct {
+		TTLSeconds int `json:"ttl_seconds"`
+	}
+	if err := r.client.doRequest("POST", "/api/v1/auth/renew", body, &result); err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(result.TTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = r.cfg.TTL
+	}
+
+	return &RenewOutput{RenewedAt: time.Now(), TTL: ttl}, nil
+}
+
+// jitter returns d plus or minus up to 10% to avoid thundering-herd
+// renewals across many clients.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	spread := int64(d) / 10
+	if spread <= 0 {
+		return d
+	}
+	return d + time.Duration(rand.Int63n(2*spread)-spread)
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRenewerRenewsAndNotifiesRenewCh(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ttl_seconds":60}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	r, err := c.NewRenewer(&RenewerConfig{
+		APIKey:      "test-key",
+		TTL:         20 * time.Millisecond,
+		RenewBuffer: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRenewer returned error: %v", err)
+	}
+
+	go r.Renew()
+	defer r.Stop()
+
+	select {
+	case out := <-r.RenewCh():
+		if out.TTL != 60*time.Second {
+			t.Fatalf("expected renewed TTL of 60s, got %v", out.TTL)
+		}
+	case err := <-r.DoneCh():
+		t.Fatalf("renewer exited early with %v", err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a renewal")
+	}
+}
+
+func TestRenewerStopDuringBackoff(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	r, err := c.NewRenewer(&RenewerConfig{
+		APIKey:      "test-key",
+		TTL:         20 * time.Millisecond,
+		RenewBuffer: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRenewer returned error: %v", err)
+	}
+
+	go r.Renew()
+
+	// Give the loop time to attempt a renewal, fail, and enter its
+	// multi-second backoff wait before we ask it to stop.
+	time.Sleep(50 * time.Millisecond)
+	r.Stop()
+
+	select {
+	case err := <-r.DoneCh():
+		if err != nil {
+			t.Fatalf("expected a clean stop (nil), got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop did not interrupt the backoff wait in time")
+	}
+}
+
+func TestRenewerGivesUpAfterConsecutiveFailures(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	r, err := c.NewRenewer(&RenewerConfig{
+		APIKey:      "test-key",
+		TTL:         20 * time.Millisecond,
+		RenewBuffer: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRenewer returned error: %v", err)
+	}
+	defer r.Stop()
+
+	// Keep this fast: give up after 1 failure instead of waiting through
+	// real exponential backoff for maxConsecutiveRenewFailures attempts.
+	r.maxFailures = 1
+
+	go r.Renew()
+
+	select {
+	case err := <-r.DoneCh():
+		if err == nil {
+			t.Fatal("expected a non-nil error after repeated renewal failures")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("renewer did not give up within the expected window")
+	}
+
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Fatalf("expected exactly 1 renewal attempt before giving up, got %d", got)
+	}
+}
+
+func TestRenewerStopIsIdempotent(t *testing.T) {
+	c := NewClient("http://example.invalid")
+	r, err := c.NewRenewer(&RenewerConfig{
+		APIKey: "test-key",
+		TTL:    time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("NewRenewer returned error: %v", err)
+	}
+
+	r.Stop()
+	r.Stop()
+}